@@ -0,0 +1,45 @@
+package pulsar
+
+import (
+	"log"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/pulsar-go/pulsar/config"
+)
+
+// challengeServer is the HTTP-01 challenge listener started by
+// serveHTTPChallenge, tracked so Shutdown can stop it alongside the main
+// server instead of leaking the :80 listener across Serve calls.
+var challengeServer *http.Server
+
+// autocertManager builds an autocert.Manager from
+// config.Settings.Certificate, restricted to the configured host allowlist.
+// It uses a DB-backed cache when config.Settings.Certificate.UseDBCache is
+// set, otherwise a local directory cache.
+func autocertManager() *autocert.Manager {
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(config.Settings.Certificate.Hosts...),
+	}
+	if config.Settings.Certificate.UseDBCache {
+		m.Cache = newCertificateCache()
+	} else {
+		m.Cache = autocert.DirCache(config.Settings.Certificate.CacheDir)
+	}
+	return m
+}
+
+// serveHTTPChallenge serves the HTTP-01 challenge handler on :80, as
+// required by autocert when the main server listens on a different port. The
+// listener is tracked in challengeServer so Shutdown can stop it, and a bind
+// failure is logged instead of being silently swallowed.
+func serveHTTPChallenge(m *autocert.Manager) {
+	challengeServer = &http.Server{Addr: ":80", Handler: m.HTTPHandler(nil)}
+	go func() {
+		if err := challengeServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Println("[PULSAR] autocert HTTP-01 challenge server failed:", err)
+		}
+	}()
+}
@@ -0,0 +1,61 @@
+package pulsar
+
+import (
+	"context"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/pulsar-go/pulsar/db"
+)
+
+// certificateRecord is the row stored for each autocert cache entry when
+// config.Settings.Certificate.UseDBCache is enabled.
+type certificateRecord struct {
+	Key  string `gorm:"primary_key"`
+	Data []byte
+}
+
+// TableName forces the table name regardless of gorm's pluralization.
+func (certificateRecord) TableName() string {
+	return "certificate_cache"
+}
+
+// dbCertificateCache is an autocert.Cache backed by the primary database
+// connection, so certificates survive restarts without relying on a shared
+// filesystem across instances.
+type dbCertificateCache struct{}
+
+// newCertificateCache creates a dbCertificateCache, ensuring its backing
+// table exists.
+func newCertificateCache() *dbCertificateCache {
+	db.Primary().AutoMigrate(&certificateRecord{})
+	return &dbCertificateCache{}
+}
+
+// Get returns the cached data for key, or autocert.ErrCacheMiss if it is not
+// present.
+func (c *dbCertificateCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var record certificateRecord
+	if err := db.Primary().Where("key = ?", key).First(&record).Error; err != nil {
+		return nil, autocert.ErrCacheMiss
+	}
+	return record.Data, nil
+}
+
+// Put stores data under key. Key is a non-zero string primary key, so
+// gorm's Save() would always take its UPDATE path and silently affect 0
+// rows the first time a key is written; check for an existing row first and
+// Create explicitly when there isn't one.
+func (c *dbCertificateCache) Put(ctx context.Context, key string, data []byte) error {
+	var record certificateRecord
+	if db.Primary().Where("key = ?", key).First(&record).Error != nil {
+		return db.Primary().Create(&certificateRecord{Key: key, Data: data}).Error
+	}
+	record.Data = data
+	return db.Primary().Save(&record).Error
+}
+
+// Delete removes the cache entry for key.
+func (c *dbCertificateCache) Delete(ctx context.Context, key string) error {
+	return db.Primary().Where("key = ?", key).Delete(&certificateRecord{}).Error
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"log"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/pulsar-go/pulsar/config"
+)
+
+// ConnectionConfig describes a single named database connection, as found in
+// config.Settings.Database.Connections.
+type ConnectionConfig struct {
+	Driver   string
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+}
+
+// connections holds every opened connection, keyed by name.
+var connections map[string]*DB
+
+// primaryName is the key of the primary connection within connections.
+var primaryName string
+
+// replicaNames lists the connections eligible for Replica(), in round-robin
+// order.
+var replicaNames []string
+
+// replicaTurn tracks the next replica to hand out from Replica(). It is
+// incremented with atomic.AddUint32 since Replica() is called concurrently
+// from request-handling goroutines.
+var replicaTurn uint32
+
+// openConnections opens every connection configured in
+// config.Settings.Database.Connections and wires up Builder, Primary and the
+// read-replica pool from config.Settings.Database.Primary/Replicas.
+func openConnections() {
+	connections = make(map[string]*DB, len(config.Settings.Database.Connections))
+	for name, cfg := range config.Settings.Database.Connections {
+		opened, err := open(cfg)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		connections[name] = opened
+	}
+	primaryName = config.Settings.Database.Primary
+	if _, ok := connections[primaryName]; !ok {
+		log.Fatalf("Database primary connection '%s' is not configured.\n", primaryName)
+	}
+	replicaNames = config.Settings.Database.Replicas
+	for _, name := range replicaNames {
+		if _, ok := connections[name]; !ok {
+			log.Fatalf("Database replica connection '%s' is not configured.\n", name)
+		}
+	}
+	// Builder stays a back-compat alias for the primary connection.
+	Builder = connections[primaryName]
+}
+
+// Connection returns the named connection, or nil if it has not been
+// configured.
+func Connection(name string) *DB {
+	return connections[name]
+}
+
+// Primary returns the primary connection. AutoMigrate and the migration
+// subsystem always run against it.
+func Primary() *DB {
+	return connections[primaryName]
+}
+
+// Replica returns one of the configured read replicas, round-robin. It
+// falls back to the primary connection when no replicas are configured.
+func Replica() *DB {
+	if len(replicaNames) == 0 {
+		return Primary()
+	}
+	turn := atomic.AddUint32(&replicaTurn, 1) - 1
+	return connections[replicaNames[turn%uint32(len(replicaNames))]]
+}
+
+// RandomReplica returns one of the configured read replicas chosen at
+// random, falling back to the primary connection when none are configured.
+func RandomReplica() *DB {
+	if len(replicaNames) == 0 {
+		return Primary()
+	}
+	return connections[replicaNames[rand.Intn(len(replicaNames))]]
+}
+
+// OnReplica returns a read replica to continue the query chain on, ignoring
+// the receiver. It lets query code opt into read-from-replica semantics,
+// e.g. db.Builder.OnReplica().Find(&users).
+func (b *DB) OnReplica() *DB {
+	return Replica()
+}
+
+// OnPrimary returns the primary connection to continue the query chain on,
+// ignoring the receiver.
+func (b *DB) OnPrimary() *DB {
+	return Primary()
+}
+
+// CloseAll closes every opened connection, primary and replicas alike. Serve
+// calls it on shutdown instead of Builder.Close so replica connections
+// opened through config.Settings.Database.Connections aren't leaked.
+func CloseAll() error {
+	var firstErr error
+	for _, connection := range connections {
+		if err := connection.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
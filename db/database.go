@@ -40,12 +40,36 @@ func AddModels(models ...interface{}) {
 	Models = append(Models, models...)
 }
 
-// Open opens a new database connection.
+// Open opens the configured database connection(s). When
+// config.Settings.Database.Connections is set, it opens every named
+// connection and makes Builder a back-compat alias for the primary one;
+// otherwise it falls back to the single top-level connection settings, as
+// before.
 func Open() {
+	if len(config.Settings.Database.Connections) > 0 {
+		openConnections()
+		return
+	}
+	dbOpened, err := open(ConnectionConfig{
+		Driver:   config.Settings.Database.Driver,
+		Host:     config.Settings.Database.Host,
+		Port:     config.Settings.Database.Port,
+		User:     config.Settings.Database.User,
+		Password: config.Settings.Database.Password,
+		Database: config.Settings.Database.Database,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	Builder = dbOpened
+	connections = map[string]*DB{"default": Builder}
+	primaryName = "default"
+}
+
+// open creates a single *DB connection for the given configuration.
+func open(s ConnectionConfig) (*DB, error) {
 	// Create the arguments
 	var args string
-	// Copy to reduce code size.
-	s := &config.Settings.Database
 	switch s.Driver {
 	case "mysql":
 		args = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", s.User, s.Password, s.Host, s.Port, s.Database)
@@ -60,18 +84,9 @@ func Open() {
 	default:
 		log.Fatalf("Database driver '%s' is not supported.\n", s.Driver)
 	}
-	// Open the database
 	dbOpened, err := gorm.Open(s.Driver, args)
 	if err != nil {
-		log.Fatalln(err)
-	}
-
-	Builder = &DB{dbOpened}
-}
-
-// clone creates a new instance of the DB
-func (b *DB) clone(lib *gorm.DB) *DB {
-	return &DB{
-		lib,
+		return nil, err
 	}
+	return &DB{dbOpened}, nil
 }
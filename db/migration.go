@@ -0,0 +1,158 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Migration represents a single, ordered schema change that can be applied
+// and reverted against the primary database connection.
+type Migration struct {
+	ID   string
+	Up   func(*gorm.DB) error
+	Down func(*gorm.DB) error
+}
+
+// schemaMigration is the row stored in the schema_migrations table for every
+// migration that has been applied.
+type schemaMigration struct {
+	ID        string `gorm:"primary_key"`
+	AppliedAt time.Time
+}
+
+// TableName forces the tracking table name regardless of gorm's pluralization.
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// migrations stores the registered migrations in registration order. They
+// are always applied/rolled back sorted by ID so registration order does
+// not need to match execution order.
+var migrations []Migration
+
+// RegisterMigration adds a migration to the set that Migrate/Rollback will
+// operate on. It should be called from an init() function or before Serve
+// starts the server.
+func RegisterMigration(migration Migration) {
+	migrations = append(migrations, migration)
+}
+
+// sortedMigrations returns the registered migrations sorted by ID.
+func sortedMigrations() []Migration {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+// ensureSchemaMigrationsTable creates the schema_migrations tracking table
+// on the primary connection if it does not exist yet.
+func ensureSchemaMigrationsTable() error {
+	return Builder.AutoMigrate(&schemaMigration{}).Error
+}
+
+// appliedMigrationIDs returns the set of migration IDs that have already
+// been applied, keyed by ID.
+func appliedMigrationIDs() (map[string]bool, error) {
+	var applied []schemaMigration
+	if err := Builder.Find(&applied).Error; err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(applied))
+	for _, a := range applied {
+		ids[a.ID] = true
+	}
+	return ids, nil
+}
+
+// Migrate runs every registered migration that has not yet been applied, in
+// ascending ID order, recording each one in the schema_migrations table.
+func Migrate() error {
+	if err := ensureSchemaMigrationsTable(); err != nil {
+		return err
+	}
+	applied, err := appliedMigrationIDs()
+	if err != nil {
+		return err
+	}
+	for _, migration := range sortedMigrations() {
+		if applied[migration.ID] {
+			continue
+		}
+		if migration.Up == nil {
+			return fmt.Errorf("migration %s has no Up function", migration.ID)
+		}
+		if err := migration.Up(Builder.DB); err != nil {
+			return fmt.Errorf("migration %s failed: %w", migration.ID, err)
+		}
+		record := schemaMigration{ID: migration.ID, AppliedAt: time.Now()}
+		if err := Builder.Create(&record).Error; err != nil {
+			return fmt.Errorf("migration %s applied but failed to record: %w", migration.ID, err)
+		}
+	}
+	return nil
+}
+
+// Rollback reverts the last `steps` applied migrations, in descending ID
+// order, removing each one from the schema_migrations table as it unwinds.
+func Rollback(steps int) error {
+	if steps <= 0 {
+		return nil
+	}
+	if err := ensureSchemaMigrationsTable(); err != nil {
+		return err
+	}
+	var applied []schemaMigration
+	if err := Builder.Order("id desc").Limit(steps).Find(&applied).Error; err != nil {
+		return err
+	}
+	byID := make(map[string]Migration, len(migrations))
+	for _, migration := range migrations {
+		byID[migration.ID] = migration
+	}
+	for _, record := range applied {
+		migration, ok := byID[record.ID]
+		if !ok {
+			return fmt.Errorf("no registered migration found for applied ID %s", record.ID)
+		}
+		if migration.Down == nil {
+			return fmt.Errorf("migration %s has no Down function", migration.ID)
+		}
+		if err := migration.Down(Builder.DB); err != nil {
+			return fmt.Errorf("rollback of migration %s failed: %w", migration.ID, err)
+		}
+		if err := Builder.Delete(&record).Error; err != nil {
+			return fmt.Errorf("migration %s rolled back but failed to untrack: %w", migration.ID, err)
+		}
+	}
+	return nil
+}
+
+// MigrationStatus describes whether a single registered migration has been
+// applied yet.
+type MigrationStatus struct {
+	ID      string
+	Applied bool
+}
+
+// MigrateStatus reports the applied/pending status of every registered
+// migration, sorted by ID.
+func MigrateStatus() ([]MigrationStatus, error) {
+	if err := ensureSchemaMigrationsTable(); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrationIDs()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range sortedMigrations() {
+		statuses = append(statuses, MigrationStatus{ID: migration.ID, Applied: applied[migration.ID]})
+	}
+	return statuses, nil
+}
@@ -0,0 +1,181 @@
+package pulsar
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pulsar-go/pulsar/config"
+)
+
+// LogLevel represents the severity of a single log line emitted by the
+// request logger.
+type LogLevel int
+
+// The log levels supported by the request logger, ordered from most to
+// least verbose.
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String returns the textual representation of the level, as it appears in
+// a log line.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Logger is implemented by anything capable of receiving structured request
+// log entries. Users can plug in their own implementation through
+// config.Settings.Server.Logger to ship logs anywhere they like.
+type Logger interface {
+	Log(level LogLevel, fields map[string]interface{})
+}
+
+// defaultLogger is the Logger used when config.Settings.Server.Logger is
+// not set. It writes one key/value line per request to an io.Writer.
+type defaultLogger struct {
+	output io.Writer
+}
+
+// logFieldOrder lists the well-known fields in the order they should appear
+// when present, ahead of any caller-specific fields such as "message".
+var logFieldOrder = []string{"request_id", "method", "path", "status", "bytes", "latency", "remote_ip", "user_agent"}
+
+// Log writes a single structured log line in "key=value" form. Well-known
+// fields are printed first in a stable order; anything else passed in
+// fields (e.g. "message" on a warning) is still printed, just afterwards.
+func (l *defaultLogger) Log(level LogLevel, fields map[string]interface{}) {
+	line := fmt.Sprintf("[PULSAR] level=%s", level)
+	printed := make(map[string]bool, len(logFieldOrder))
+	for _, key := range logFieldOrder {
+		if value, ok := fields[key]; ok {
+			line += fmt.Sprintf(" %s=%v", key, value)
+			printed[key] = true
+		}
+	}
+	for key, value := range fields {
+		if !printed[key] {
+			line += fmt.Sprintf(" %s=%v", key, value)
+		}
+	}
+	fmt.Fprintln(l.output, line)
+}
+
+// logger returns the Logger to use for the current request, falling back to
+// a stderr-backed default when the application has not configured one.
+func logger() Logger {
+	if config.Settings.Server.Logger != nil {
+		return config.Settings.Server.Logger
+	}
+	output := config.Settings.Server.LogOutput
+	if output == nil {
+		output = os.Stderr
+	}
+	return &defaultLogger{output: output}
+}
+
+// logLevel returns the minimum level the application wants logged, defaulting
+// to LogLevelInfo so debug-level noise is opt-in.
+func logLevel() LogLevel {
+	switch config.Settings.Server.LogLevel {
+	case "debug":
+		return LogLevelDebug
+	case "warn":
+		return LogLevelWarn
+	case "error":
+		return LogLevelError
+	default:
+		return LogLevelInfo
+	}
+}
+
+// responseRecorder wraps a http.ResponseWriter so the logging middleware can
+// observe the status code and byte count written by the route handler. It
+// also runs beforeWrite, if set, exactly once before the first byte or
+// header hits the underlying writer, so callers can still mutate response
+// headers (e.g. to persist a session cookie) right up until that point.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	beforeWrite func()
+	fired       bool
+}
+
+// fireBeforeWrite runs beforeWrite the first time it is called and never
+// again, so headers set from it land before anything is flushed.
+func (rec *responseRecorder) fireBeforeWrite() {
+	if rec.fired {
+		return
+	}
+	rec.fired = true
+	if rec.beforeWrite != nil {
+		rec.beforeWrite()
+	}
+}
+
+// WriteHeader records the status code before delegating to the underlying
+// writer.
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.fireBeforeWrite()
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written before delegating to the
+// underlying writer. It also defaults the status to 200, matching the
+// standard library's behaviour when WriteHeader is never called explicitly.
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.fireBeforeWrite()
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// newRequestID generates a random, URL-safe request identifier used both for
+// the X-Request-ID response header and structured log correlation.
+func newRequestID() string {
+	buff := make([]byte, 16)
+	if _, err := rand.Read(buff); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return fmt.Sprintf("%x", buff)
+}
+
+// logRequest emits a single structured log line for a completed request at
+// the given level, provided it meets the configured minimum level.
+func logRequest(level LogLevel, r *http.Request, rec *responseRecorder, requestID string, start time.Time) {
+	if level < logLevel() {
+		return
+	}
+	logger().Log(level, map[string]interface{}{
+		"request_id": requestID,
+		"method":     r.Method,
+		"path":       r.URL.Path,
+		"status":     rec.status,
+		"bytes":      rec.bytes,
+		"latency":    time.Since(start).String(),
+		"remote_ip":  r.RemoteAddr,
+		"user_agent": r.UserAgent(),
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/kabukky/httpscerts"
@@ -16,6 +17,7 @@ import (
 	"github.com/pulsar-go/pulsar/queue"
 	"github.com/pulsar-go/pulsar/request"
 	"github.com/pulsar-go/pulsar/router"
+	"github.com/pulsar-go/pulsar/session"
 	"github.com/rs/cors"
 )
 
@@ -28,28 +30,58 @@ func fileExists(path string) bool {
 // debugHandler is responsible for each http handler in debug mode.
 func developmentHandler(route *router.Route) func(http.ResponseWriter, *http.Request, httprouter.Params) {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-		log.Printf("[PULSAR] Request %s\n", r.URL)
-		req := &request.HTTP{Request: r, Writer: w, Params: ps}
+		start := time.Now()
+		requestID := newRequestID()
+		w.Header().Set("X-Request-ID", requestID)
+		rec := &responseRecorder{ResponseWriter: w}
+		req := &request.HTTP{Request: r, Writer: rec, Params: ps, RequestID: requestID}
 		buff, err := ioutil.ReadAll(req.Request.Body)
 		if err != nil {
-			log.Printf("[PULSAR] Failed to read the request body\n")
+			logger().Log(LogLevelWarn, map[string]interface{}{"request_id": requestID, "message": "failed to read the request body"})
 		}
 		req.Body = string(buff)
 		req.Request.Body = ioutil.NopCloser(bytes.NewBuffer(buff))
+		sess, err := session.Load(r)
+		if err != nil {
+			logger().Log(LogLevelWarn, map[string]interface{}{"request_id": requestID, "message": "failed to load session"})
+			sess = session.New()
+		}
+		req.SetSession(sess)
+		rec.beforeWrite = func() {
+			if err := session.Persist(rec.ResponseWriter, sess); err != nil {
+				logger().Log(LogLevelWarn, map[string]interface{}{"request_id": requestID, "message": "failed to persist session"})
+			}
+		}
 		res := route.Handler(req)
 		res.Handle(req)
+		rec.fireBeforeWrite()
+		logRequest(LogLevelInfo, r, rec, requestID, start)
 	}
 }
 
 // productionHandler is responsible for each http handler in debug mode.
 func productionHandler(route *router.Route) func(http.ResponseWriter, *http.Request, httprouter.Params) {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-		req := &request.HTTP{Request: r, Writer: w, Params: ps}
+		start := time.Now()
+		requestID := newRequestID()
+		w.Header().Set("X-Request-ID", requestID)
+		rec := &responseRecorder{ResponseWriter: w}
+		req := &request.HTTP{Request: r, Writer: rec, Params: ps, RequestID: requestID}
 		buff, _ := ioutil.ReadAll(req.Request.Body)
 		req.Body = string(buff)
 		req.Request.Body = ioutil.NopCloser(bytes.NewBuffer(buff))
+		sess, err := session.Load(r)
+		if err != nil {
+			sess = session.New()
+		}
+		req.SetSession(sess)
+		rec.beforeWrite = func() {
+			session.Persist(rec.ResponseWriter, sess)
+		}
 		res := route.Handler(req)
 		res.Handle(req)
+		rec.fireBeforeWrite()
+		logRequest(LogLevelInfo, r, rec, requestID, start)
 	}
 }
 
@@ -87,6 +119,7 @@ func RegisterRoutes(mux *httprouter.Router, r *router.Router) {
 
 // Serve starts the server.
 func Serve() error {
+	resetShutdownState()
 	router := &router.Routes
 	mux := httprouter.New()
 	// Register the application routes.
@@ -107,9 +140,15 @@ func Serve() error {
 	generateSSLCertificate(address)
 	// Set the database configuration
 	db.Open()
-	defer db.Builder.Close()
+	defer db.CloseAll()
+	// Set up the session store.
+	session.Open()
 	// Migrate if nessesary
-	if config.Settings.Database.AutoMigrate {
+	if config.Settings.Database.Migrations {
+		if err := db.Migrate(); err != nil {
+			log.Fatalln(err)
+		}
+	} else if config.Settings.Database.AutoMigrate {
 		db.Builder.AutoMigrate(db.Models...)
 	}
 	// Configure the queue system.
@@ -119,6 +158,8 @@ func Serve() error {
 	}
 	queue.NewPool(int(routines))
 	defer queue.Pool.Release()
+	queue.StartScheduler()
+	defer queue.StopScheduler()
 	if config.Settings.Server.Development {
 		fmt.Println("-----------------------------------------------------")
 		fmt.Println("|                                                   |")
@@ -131,23 +172,47 @@ func Serve() error {
 		fmt.Println("-----------------------------------------------------")
 		fmt.Println()
 	}
+	server = &http.Server{Addr: address, Handler: handler}
+	go waitForSignal()
 	if config.Settings.Certificate.Enabled {
+		if config.Settings.Certificate.Mode == "autocert" {
+			manager := autocertManager()
+			serveHTTPChallenge(manager)
+			server.TLSConfig = manager.TLSConfig()
+			if config.Settings.Server.Development {
+				fmt.Printf("Creating a HTTP/2 server with autocert TLS on %s\n\n", address)
+			}
+			if err := server.ListenAndServeTLS("", ""); err != http.ErrServerClosed {
+				return err
+			}
+			<-shutdownComplete
+			return shutdownErr
+		}
 		if config.Settings.Server.Development {
 			fmt.Printf("Creating a HTTP/2 server with TLS on %s\n", address)
 			fmt.Printf("Certificate: %s\nKey: %s\n\n", config.Settings.Certificate.CertFile, config.Settings.Certificate.KeyFile)
 		}
-		return http.ListenAndServeTLS(address, config.Settings.Certificate.CertFile, config.Settings.Certificate.KeyFile, handler)
+		if err := server.ListenAndServeTLS(config.Settings.Certificate.CertFile, config.Settings.Certificate.KeyFile); err != http.ErrServerClosed {
+			return err
+		}
+		<-shutdownComplete
+		return shutdownErr
 	}
 	if config.Settings.Server.Development {
 		fmt.Printf("Creating a HTTP/1.1 server on %s\n\n", address)
 	}
-	return http.ListenAndServe(address, handler)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	<-shutdownComplete
+	return shutdownErr
 }
 
-// generateSSLCertificate creates an ssl certificate if https is enabled
+// generateSSLCertificate creates a self-signed ssl certificate if https is
+// enabled and config.Settings.Certificate.Mode is "selfsigned". The "file"
+// and "autocert" modes manage their own certificates.
 func generateSSLCertificate(address string) {
-	// Generate a SSL certificate if needed.
-	if !config.Settings.Certificate.Enabled {
+	if !config.Settings.Certificate.Enabled || config.Settings.Certificate.Mode != "selfsigned" {
 		return
 	}
 
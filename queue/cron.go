@@ -0,0 +1,92 @@
+package queue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cronField matches a single field of a 5-field cron spec against a value in
+// [min, max].
+type cronField struct {
+	min    int
+	every  int
+	values map[int]bool // nil for "*" and "*/n", which match through min and every
+}
+
+// matches reports whether value satisfies the field. For a "*/n" step, real
+// cron tools step from the field's minimum rather than from zero, so "*/5"
+// on day-of-month (min 1) lines up on 1, 6, 11... rather than 5, 10, 15...
+func (f cronField) matches(value int) bool {
+	if f.values == nil {
+		return f.every <= 1 || (value-f.min)%f.every == 0
+	}
+	return f.values[value]
+}
+
+// cronSpec is a parsed 5-field "minute hour day month weekday" cron
+// expression.
+type cronSpec struct {
+	minute, hour, day, month, weekday cronField
+}
+
+// parseCron parses a standard 5-field cron expression (minute hour day
+// month weekday), supporting "*", "*/n", "a-b" and comma-separated lists.
+func parseCron(spec string) (cronSpec, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("queue: invalid cron spec %q, expected 5 fields", spec)
+	}
+	ranges := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}}
+	parsed := make([]cronField, 5)
+	for i, field := range fields {
+		f, err := parseCronField(field, ranges[i][0], ranges[i][1])
+		if err != nil {
+			return cronSpec{}, fmt.Errorf("queue: invalid cron spec %q: %w", spec, err)
+		}
+		parsed[i] = f
+	}
+	return cronSpec{minute: parsed[0], hour: parsed[1], day: parsed[2], month: parsed[3], weekday: parsed[4]}, nil
+}
+
+// parseCronField parses a single cron field within [min, max].
+func parseCronField(field string, min, max int) (cronField, error) {
+	if field == "*" {
+		return cronField{min: min, every: 1}, nil
+	}
+	if strings.HasPrefix(field, "*/") {
+		step, err := strconv.Atoi(field[2:])
+		if err != nil || step <= 0 {
+			return cronField{}, fmt.Errorf("invalid step %q", field)
+		}
+		return cronField{min: min, every: step}, nil
+	}
+	values := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		if lo, hi, ok := strings.Cut(part, "-"); ok {
+			from, err := strconv.Atoi(lo)
+			if err != nil {
+				return cronField{}, fmt.Errorf("invalid range %q", part)
+			}
+			to, err := strconv.Atoi(hi)
+			if err != nil {
+				return cronField{}, fmt.Errorf("invalid range %q", part)
+			}
+			for v := from; v <= to; v++ {
+				values[v] = true
+			}
+			continue
+		}
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return cronField{}, fmt.Errorf("invalid value %q", part)
+		}
+		values[v] = true
+	}
+	for v := range values {
+		if v < min || v > max {
+			return cronField{}, fmt.Errorf("value %d out of range [%d, %d]", v, min, max)
+		}
+	}
+	return cronField{values: values}, nil
+}
@@ -0,0 +1,64 @@
+package queue
+
+import "testing"
+
+func TestParseCronField(t *testing.T) {
+	cases := []struct {
+		field     string
+		min, max  int
+		value     int
+		wantMatch bool
+		wantErr   bool
+	}{
+		{field: "*", min: 0, max: 59, value: 37, wantMatch: true},
+		{field: "*/5", min: 0, max: 59, value: 10, wantMatch: true},
+		{field: "*/5", min: 0, max: 59, value: 12, wantMatch: false},
+		// day-of-month's min is 1, so "*/5" steps from 1 (1, 6, 11, ...),
+		// not from 0 (5, 10, 15, ...).
+		{field: "*/5", min: 1, max: 31, value: 6, wantMatch: true},
+		{field: "*/5", min: 1, max: 31, value: 5, wantMatch: false},
+		{field: "1-3", min: 0, max: 59, value: 2, wantMatch: true},
+		{field: "1-3", min: 0, max: 59, value: 4, wantMatch: false},
+		{field: "1,3,5", min: 0, max: 59, value: 3, wantMatch: true},
+		{field: "1,3,5", min: 0, max: 59, value: 2, wantMatch: false},
+		{field: "60", min: 0, max: 59, wantErr: true},
+		{field: "abc", min: 0, max: 59, wantErr: true},
+	}
+	for _, c := range cases {
+		f, err := parseCronField(c.field, c.min, c.max)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseCronField(%q, %d, %d): expected error, got none", c.field, c.min, c.max)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("parseCronField(%q, %d, %d): unexpected error: %v", c.field, c.min, c.max, err)
+		}
+		if got := f.matches(c.value); got != c.wantMatch {
+			t.Errorf("parseCronField(%q, %d, %d).matches(%d) = %v, want %v", c.field, c.min, c.max, c.value, got, c.wantMatch)
+		}
+	}
+}
+
+func TestParseCronInvalidFieldCount(t *testing.T) {
+	if _, err := parseCron("* * * *"); err == nil {
+		t.Error("parseCron with 4 fields: expected error, got none")
+	}
+}
+
+func TestParseCronSpecFields(t *testing.T) {
+	spec, err := parseCron("*/15 9 1 * *")
+	if err != nil {
+		t.Fatalf("parseCron: unexpected error: %v", err)
+	}
+	if !spec.minute.matches(0) || !spec.minute.matches(15) || spec.minute.matches(20) {
+		t.Error("minute field did not match the expected */15 values")
+	}
+	if !spec.hour.matches(9) || spec.hour.matches(10) {
+		t.Error("hour field did not match the expected exact value")
+	}
+	if !spec.day.matches(1) || spec.day.matches(2) {
+		t.Error("day field did not match the expected exact value")
+	}
+}
@@ -0,0 +1,57 @@
+package queue
+
+import "sync"
+
+// Job is a unit of work dispatched to the worker pool.
+type Job func()
+
+// WorkerPool runs dispatched Jobs across a fixed number of worker
+// goroutines.
+type WorkerPool struct {
+	jobs   chan Job
+	wg     sync.WaitGroup
+	active sync.WaitGroup
+}
+
+// Pool is the worker pool configured by NewPool. Dispatch and the scheduler
+// both submit work to it.
+var Pool *WorkerPool
+
+// NewPool starts a worker pool with the given number of routines and
+// assigns it to Pool.
+func NewPool(routines int) {
+	pool := &WorkerPool{jobs: make(chan Job)}
+	for i := 0; i < routines; i++ {
+		pool.wg.Add(1)
+		go pool.work()
+	}
+	Pool = pool
+}
+
+// work runs jobs off the pool's channel until it is closed.
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		p.active.Add(1)
+		job()
+		p.active.Done()
+	}
+}
+
+// Dispatch queues job to be run by the next available worker.
+func (p *WorkerPool) Dispatch(job Job) {
+	p.jobs <- job
+}
+
+// Wait blocks until every job currently being worked on has finished. It
+// does not wait for jobs dispatched after it was called.
+func (p *WorkerPool) Wait() {
+	p.active.Wait()
+}
+
+// Release stops accepting new jobs and waits for the worker goroutines to
+// exit.
+func (p *WorkerPool) Release() {
+	close(p.jobs)
+	p.wg.Wait()
+}
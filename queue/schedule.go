@@ -0,0 +1,220 @@
+package queue
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pulsar-go/pulsar/db"
+)
+
+// ScheduledJob describes a single cron-scheduled job, as returned by
+// ListSchedules.
+type ScheduledJob struct {
+	// Name uniquely identifies this schedule for distributed-lease purposes.
+	// Two jobs registered with the same Spec (e.g. two separate
+	// EveryMinute calls) get distinct Names so they don't collide on the
+	// same lease row.
+	Name string
+	Spec string
+	job  func()
+	spec cronSpec
+}
+
+var (
+	schedulesMu sync.Mutex
+	schedules   []*ScheduledJob
+	scheduleSeq map[string]int = map[string]int{}
+	tickerStop  chan struct{}
+	tickerDone  chan struct{}
+)
+
+// Schedule registers job to run whenever spec next matches, using standard
+// 5-field cron syntax ("*/5 * * * *"). It fails fast on an invalid spec, the
+// same way Open fails fast on an unsupported database driver.
+func Schedule(spec string, job func()) {
+	parsed, err := parseCron(spec)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	schedulesMu.Lock()
+	defer schedulesMu.Unlock()
+	scheduleSeq[spec]++
+	name := fmt.Sprintf("%s#%d", spec, scheduleSeq[spec])
+	schedules = append(schedules, &ScheduledJob{Name: name, Spec: spec, job: job, spec: parsed})
+}
+
+// EveryMinute schedules job to run once every minute.
+func EveryMinute(job func()) {
+	Schedule("* * * * *", job)
+}
+
+// Hourly schedules job to run at the top of every hour.
+func Hourly(job func()) {
+	Schedule("0 * * * *", job)
+}
+
+// DailyAt schedules job to run once a day at the given "HH:MM" clock time.
+func DailyAt(clock string, job func()) {
+	hour, minute, err := parseClock(clock)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	Schedule(fmt.Sprintf("%d %d * * *", minute, hour), job)
+}
+
+// parseClock parses a "HH:MM" string into its hour and minute components.
+func parseClock(clock string) (hour int, minute int, err error) {
+	parts := strings.Split(clock, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("queue: invalid clock time %q, expected \"HH:MM\"", clock)
+	}
+	hour, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("queue: invalid clock time %q: %w", clock, err)
+	}
+	minute, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("queue: invalid clock time %q: %w", clock, err)
+	}
+	return hour, minute, nil
+}
+
+// ListSchedules returns every registered schedule, for an admin/debug
+// endpoint.
+func ListSchedules() []ScheduledJob {
+	schedulesMu.Lock()
+	defer schedulesMu.Unlock()
+	list := make([]ScheduledJob, 0, len(schedules))
+	for _, s := range schedules {
+		list = append(list, ScheduledJob{Name: s.Name, Spec: s.Spec})
+	}
+	return list
+}
+
+// StartScheduler begins ticking once a minute, dispatching every due
+// schedule into Pool. Only one instance across a fleet actually runs a given
+// job, coordinated through a db-backed lease per schedule. It must be
+// started after NewPool.
+func StartScheduler() {
+	tickerStop = make(chan struct{})
+	tickerDone = make(chan struct{})
+	go runScheduler()
+}
+
+// StopScheduler stops the scheduler's ticker and waits for it to exit. It is
+// safe to call even if StartScheduler was never called.
+func StopScheduler() {
+	if tickerStop == nil {
+		return
+	}
+	close(tickerStop)
+	<-tickerDone
+}
+
+// runScheduler ticks once a minute, dispatching due jobs into Pool.
+func runScheduler() {
+	defer close(tickerDone)
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tickerStop:
+			return
+		case now := <-ticker.C:
+			dispatchDue(now)
+		}
+	}
+}
+
+// dispatchDue dispatches every schedule whose cron spec matches now, each
+// guarded by its own lease so only one app instance runs it.
+func dispatchDue(now time.Time) {
+	schedulesMu.Lock()
+	due := make([]*ScheduledJob, 0, len(schedules))
+	for _, s := range schedules {
+		if matchesCron(s.spec, now) {
+			due = append(due, s)
+		}
+	}
+	schedulesMu.Unlock()
+	for _, s := range due {
+		job := s.job
+		lease := s.Name
+		Pool.Dispatch(func() {
+			if !acquireLease(lease, now) {
+				return
+			}
+			job()
+		})
+	}
+}
+
+// matchesCron reports whether now satisfies every field of spec.
+func matchesCron(spec cronSpec, now time.Time) bool {
+	return spec.minute.matches(now.Minute()) &&
+		spec.hour.matches(now.Hour()) &&
+		spec.day.matches(now.Day()) &&
+		spec.month.matches(int(now.Month())) &&
+		spec.weekday.matches(int(now.Weekday()))
+}
+
+// scheduleLease tracks which app instance currently owns the right to run a
+// given schedule, so multiple instances of the same app don't double-run it.
+type scheduleLease struct {
+	Name        string `gorm:"primary_key"`
+	LockedUntil time.Time
+}
+
+// TableName forces the table name regardless of gorm's pluralization.
+func (scheduleLease) TableName() string {
+	return "schedule_leases"
+}
+
+// leaseDuration is how long a dispatched run holds the lease before another
+// instance is allowed to try it again, a safety net against a worker that
+// died mid-job.
+const leaseDuration = 50 * time.Second
+
+// acquireLease attempts to claim the lease for name, returning true only if
+// this instance may proceed to run the job for the given tick. Whether to
+// INSERT or UPDATE the lease row is decided explicitly from the SELECT
+// above: gorm's Save() picks INSERT vs UPDATE purely by checking the
+// primary key for its zero value, and scheduleLease.Name (a string) is
+// never zero, so a blind Save() would silently UPDATE 0 rows on the very
+// first acquisition of a given name instead of inserting it.
+func acquireLease(name string, tick time.Time) bool {
+	primary := db.Primary()
+	primary.AutoMigrate(&scheduleLease{})
+	tx := primary.Begin()
+	var lease scheduleLease
+	found := tx.Set("gorm:query_option", "FOR UPDATE").Where("name = ?", name).First(&lease).Error == nil
+	now := time.Now()
+	if leaseHeld(found, lease.LockedUntil, now) {
+		tx.Rollback()
+		return false
+	}
+	lease = scheduleLease{Name: name, LockedUntil: now.Add(leaseDuration)}
+	var err error
+	if found {
+		err = tx.Save(&lease).Error
+	} else {
+		err = tx.Create(&lease).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return false
+	}
+	return tx.Commit().Error == nil
+}
+
+// leaseHeld reports whether an existing lease row still belongs to another
+// instance, i.e. whether this instance must back off instead of claiming it.
+// Split out from acquireLease so the decision can be unit tested without a
+// database.
+func leaseHeld(found bool, lockedUntil time.Time, now time.Time) bool {
+	return found && lockedUntil.After(now)
+}
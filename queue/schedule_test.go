@@ -0,0 +1,25 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaseHeld(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name        string
+		found       bool
+		lockedUntil time.Time
+		want        bool
+	}{
+		{name: "no existing row", found: false, lockedUntil: now.Add(time.Minute), want: false},
+		{name: "lease still in the future", found: true, lockedUntil: now.Add(time.Minute), want: true},
+		{name: "lease expired", found: true, lockedUntil: now.Add(-time.Minute), want: false},
+	}
+	for _, c := range cases {
+		if got := leaseHeld(c.found, c.lockedUntil, now); got != c.want {
+			t.Errorf("%s: leaseHeld() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
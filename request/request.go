@@ -0,0 +1,43 @@
+package request
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pulsar-go/pulsar/session"
+)
+
+// Method represents the HTTP method of a route/request.
+type Method int
+
+// The supported HTTP methods.
+const (
+	GetRequest Method = iota
+	HeadRequest
+	PostRequest
+	PutRequest
+	PatchRequest
+	DeleteRequest
+)
+
+// HTTP represents an incoming request as it is passed to route handlers.
+type HTTP struct {
+	Request   *http.Request
+	Writer    http.ResponseWriter
+	Params    httprouter.Params
+	Body      string
+	RequestID string
+	sess      *session.Session
+}
+
+// SetSession attaches a session to the request. It is called by the pulsar
+// handlers before the route handler runs, once the session cookie/ID has
+// been resolved.
+func (h *HTTP) SetSession(sess *session.Session) {
+	h.sess = sess
+}
+
+// Session returns the session attached to this request.
+func (h *HTTP) Session() *session.Session {
+	return h.sess
+}
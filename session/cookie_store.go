@@ -0,0 +1,111 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// CookieStore is a Store that keeps the session values in the cookie itself,
+// gorilla/securecookie-style: JSON-encode the values, encrypt them with
+// AES-GCM, then authenticate the result with HMAC-SHA256, all derived from a
+// single application secret. There is nothing to read/write server-side, so
+// the "id" passed around is the encoded, encrypted payload.
+type CookieStore struct {
+	hashKey  []byte
+	blockKey []byte
+}
+
+// NewCookieStore derives a CookieStore from the given secret. The secret
+// should be at least 32 bytes of random data; it is hashed to produce both
+// the HMAC and AES keys.
+func NewCookieStore(secret string) *CookieStore {
+	hashKey := sha256.Sum256([]byte("pulsar-session-hmac:" + secret))
+	blockKey := sha256.Sum256([]byte("pulsar-session-aes:" + secret))
+	return &CookieStore{hashKey: hashKey[:], blockKey: blockKey[:]}
+}
+
+// Read decrypts and verifies the cookie payload passed as id, returning the
+// values it encodes.
+func (c *CookieStore) Read(id string) (map[string]interface{}, error) {
+	raw, err := base64.URLEncoding.DecodeString(id)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, c.hashKey)
+	if len(raw) < mac.Size() {
+		return nil, errors.New("session: cookie payload too short")
+	}
+	signature, ciphertext := raw[:mac.Size()], raw[mac.Size():]
+	mac.Write(ciphertext)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errors.New("session: cookie signature mismatch")
+	}
+	block, err := aes.NewCipher(c.blockKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("session: cookie ciphertext too short")
+	}
+	nonce, data := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Write is a no-op for CookieStore and always returns nil. The encrypted,
+// signed payload for values comes from Encode, which callers send back as
+// the cookie value.
+func (c *CookieStore) Write(id string, values map[string]interface{}) error {
+	// CookieStore has no server-side state to update: the new payload is
+	// produced by Encode and sent back out as the cookie value instead.
+	return nil
+}
+
+// Encode returns the encrypted, signed cookie payload for values.
+func (c *CookieStore) Encode(values map[string]interface{}) (string, error) {
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(c.blockKey)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	mac := hmac.New(sha256.New, c.hashKey)
+	mac.Write(ciphertext)
+	payload := append(mac.Sum(nil), ciphertext...)
+	return base64.URLEncoding.EncodeToString(payload), nil
+}
+
+// Destroy is a no-op for CookieStore: clearing the session means sending an
+// expired cookie, which is the caller's responsibility.
+func (c *CookieStore) Destroy(id string) error {
+	return nil
+}
@@ -0,0 +1,50 @@
+package session
+
+import "testing"
+
+func TestCookieStoreEncodeReadRoundTrip(t *testing.T) {
+	store := NewCookieStore("a test secret that is long enough")
+	values := map[string]interface{}{"user_id": "42", "_flashes": []Flash{{Type: "success", Message: "saved"}}}
+
+	encoded, err := store.Encode(values)
+	if err != nil {
+		t.Fatalf("Encode: unexpected error: %v", err)
+	}
+
+	decoded, err := store.Read(encoded)
+	if err != nil {
+		t.Fatalf("Read: unexpected error: %v", err)
+	}
+	if decoded["user_id"] != "42" {
+		t.Errorf("decoded user_id = %v, want %q", decoded["user_id"], "42")
+	}
+	flashes := decodeFlashes(decoded["_flashes"])
+	if len(flashes) != 1 || flashes[0].Type != "success" || flashes[0].Message != "saved" {
+		t.Errorf("decoded flashes = %+v, want one success/saved flash", flashes)
+	}
+}
+
+func TestCookieStoreReadRejectsTamperedPayload(t *testing.T) {
+	store := NewCookieStore("a test secret that is long enough")
+	encoded, err := store.Encode(map[string]interface{}{"user_id": "42"})
+	if err != nil {
+		t.Fatalf("Encode: unexpected error: %v", err)
+	}
+	tampered := []byte(encoded)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := store.Read(string(tampered)); err == nil {
+		t.Error("Read on a tampered payload: expected error, got none")
+	}
+}
+
+func TestCookieStoreReadRejectsDifferentSecret(t *testing.T) {
+	a := NewCookieStore("first secret that is long enough")
+	b := NewCookieStore("second secret that is long enough")
+	encoded, err := a.Encode(map[string]interface{}{"user_id": "42"})
+	if err != nil {
+		t.Fatalf("Encode: unexpected error: %v", err)
+	}
+	if _, err := b.Read(encoded); err == nil {
+		t.Error("Read with a different secret: expected error, got none")
+	}
+}
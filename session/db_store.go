@@ -0,0 +1,69 @@
+package session
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pulsar-go/pulsar/db"
+)
+
+// sessionRecord is the row persisted by DBStore for each session.
+type sessionRecord struct {
+	ID        string `gorm:"primary_key"`
+	Values    string
+	UpdatedAt time.Time
+}
+
+// TableName forces the table name regardless of gorm's pluralization.
+func (sessionRecord) TableName() string {
+	return "sessions"
+}
+
+// DBStore is a Store backed by a GORM table through db.Builder. It suits
+// applications that already run a database and want sessions to survive
+// restarts without a dedicated cache.
+type DBStore struct{}
+
+// NewDBStore creates a DBStore, ensuring its backing table exists.
+func NewDBStore() *DBStore {
+	db.Builder.AutoMigrate(&sessionRecord{})
+	return &DBStore{}
+}
+
+// Read loads the values stored for id, returning a nil map if the session is
+// unknown.
+func (s *DBStore) Read(id string) (map[string]interface{}, error) {
+	var record sessionRecord
+	if err := db.Builder.Where("id = ?", id).First(&record).Error; err != nil {
+		return nil, nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(record.Values), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Write upserts the values stored for id. ID is a non-zero string primary
+// key, so gorm's Save() would always take its UPDATE path and silently
+// affect 0 rows the first time a session is written; check for an existing
+// row first and Create explicitly when there isn't one.
+func (s *DBStore) Write(id string, values map[string]interface{}) error {
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	var record sessionRecord
+	if db.Builder.Where("id = ?", id).First(&record).Error != nil {
+		record = sessionRecord{ID: id, Values: string(encoded), UpdatedAt: time.Now()}
+		return db.Builder.Create(&record).Error
+	}
+	record.Values = string(encoded)
+	record.UpdatedAt = time.Now()
+	return db.Builder.Save(&record).Error
+}
+
+// Destroy removes the session identified by id.
+func (s *DBStore) Destroy(id string) error {
+	return db.Builder.Where("id = ?", id).Delete(&sessionRecord{}).Error
+}
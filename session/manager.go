@@ -0,0 +1,61 @@
+package session
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/pulsar-go/pulsar/config"
+)
+
+// Open selects and initializes the Store configured through
+// config.Settings.Session.Driver. It must run after db.Open() when the
+// "db" driver is selected, since DBStore depends on db.Builder.
+func Open() {
+	s := &config.Settings.Session
+	switch s.Driver {
+	case "cookie":
+		store = NewCookieStore(s.Secret)
+	case "db":
+		store = NewDBStore()
+	default:
+		log.Fatalf("Session driver '%s' is not supported.\n", s.Driver)
+	}
+}
+
+// CookieName is the name of the cookie used to carry the session ID (or, for
+// CookieStore, the whole encrypted payload).
+const CookieName = "pulsar_session"
+
+// Load resolves the session for an incoming request from its session
+// cookie, starting a new session if none is present. A cookie the Store
+// cannot read is reported as an error instead.
+func Load(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		return New(), nil
+	}
+	return For(cookie.Value)
+}
+
+// Persist saves sess and writes its cookie onto the response. For a
+// CookieStore backend the cookie value is the freshly encoded payload;
+// otherwise it is the session ID handed to the configured Store.
+func Persist(w http.ResponseWriter, sess *Session) error {
+	if err := sess.Save(); err != nil {
+		return err
+	}
+	value := sess.ID()
+	if cookieStore, ok := store.(*CookieStore); ok {
+		// Always encode a fresh snapshot, not sess.values directly: Save is a
+		// no-op when the session isn't dirty, so sess.values may be missing
+		// "_flashes" even though sess.flashes still holds messages queued
+		// earlier this request that haven't been read yet.
+		encoded, err := cookieStore.Encode(sess.snapshot())
+		if err != nil {
+			return err
+		}
+		value = encoded
+	}
+	http.SetCookie(w, &http.Cookie{Name: CookieName, Value: value, HttpOnly: true, Path: "/"})
+	return nil
+}
@@ -0,0 +1,145 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+)
+
+// Flash represents a single one-shot message queued for the next request.
+type Flash struct {
+	Type    string
+	Message string
+}
+
+// Session represents a single user session, backed by whichever Store the
+// application has configured.
+type Session struct {
+	id      string
+	store   Store
+	values  map[string]interface{}
+	flashes []Flash
+	dirty   bool
+}
+
+// newSession wraps the data read from a Store into a Session. A nil values
+// map starts a fresh, empty session.
+func newSession(id string, store Store, values map[string]interface{}) *Session {
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	flashes := decodeFlashes(values["_flashes"])
+	delete(values, "_flashes")
+	return &Session{id: id, store: store, values: values, flashes: flashes}
+}
+
+// decodeFlashes recovers a []Flash from the "_flashes" entry of a values map
+// that has round-tripped through a Store. Both CookieStore and DBStore
+// persist values as JSON, so after a reload raw is a []interface{} of
+// map[string]interface{}, not the concrete []Flash it started as;
+// re-encoding and decoding through JSON converts it back reliably either
+// way.
+func decodeFlashes(raw interface{}) []Flash {
+	if raw == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var flashes []Flash
+	if err := json.Unmarshal(encoded, &flashes); err != nil {
+		return nil
+	}
+	return flashes
+}
+
+// ID returns the session identifier.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Get returns the value stored under key, or nil if it is not set.
+func (s *Session) Get(key string) interface{} {
+	return s.values[key]
+}
+
+// Set stores value under key.
+func (s *Session) Set(key string, value interface{}) {
+	s.values[key] = value
+	s.dirty = true
+}
+
+// Delete removes the value stored under key.
+func (s *Session) Delete(key string) {
+	delete(s.values, key)
+	s.dirty = true
+}
+
+// Flash queues a one-shot message of the given type to be read by Flashes on
+// the next request, after which it is discarded.
+func (s *Session) Flash(kind string, message string) {
+	s.flashes = append(s.flashes, Flash{Type: kind, Message: message})
+	s.dirty = true
+}
+
+// Flashes returns and clears every queued flash message.
+func (s *Session) Flashes() []Flash {
+	flashes := s.flashes
+	s.flashes = nil
+	s.dirty = true
+	return flashes
+}
+
+// CSRFToken returns the session's CSRF token, generating and persisting one
+// the first time it is requested.
+func (s *Session) CSRFToken() string {
+	if token, ok := s.values["_csrf"].(string); ok && token != "" {
+		return token
+	}
+	token := generateToken()
+	s.Set("_csrf", token)
+	return token
+}
+
+// ValidCSRFToken reports whether the given token matches the session's CSRF
+// token.
+func (s *Session) ValidCSRFToken(token string) bool {
+	return token != "" && token == s.CSRFToken()
+}
+
+// Save persists the session through its Store if it has been modified.
+func (s *Session) Save() error {
+	if !s.dirty {
+		return nil
+	}
+	if err := s.store.Write(s.id, s.snapshot()); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// snapshot returns a copy of values with the live flashes embedded under
+// "_flashes". It is used both by Save and, independent of the dirty check,
+// by Persist when encoding a fresh CookieStore cookie - the cookie payload
+// must always reflect the current flashes, not just whatever was last
+// written through Save.
+func (s *Session) snapshot() map[string]interface{} {
+	values := make(map[string]interface{}, len(s.values)+1)
+	for key, value := range s.values {
+		values[key] = value
+	}
+	values["_flashes"] = s.flashes
+	return values
+}
+
+// generateToken returns a random, hex-encoded token suitable for session IDs
+// and CSRF tokens.
+func generateToken() string {
+	buff := make([]byte, 32)
+	if _, err := rand.Read(buff); err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%x", buff)
+}
@@ -0,0 +1,49 @@
+package session
+
+// Store is implemented by anything able to read and persist session values
+// keyed by session ID. Open selects the backend from
+// config.Settings.Session.Driver, which accepts only the built-in "cookie"
+// and "db" stores.
+type Store interface {
+	// Read returns the values stored for id, or a nil map if the session is
+	// new or unknown.
+	Read(id string) (map[string]interface{}, error)
+	// Write persists values for id, creating the session if needed.
+	Write(id string, values map[string]interface{}) error
+	// Destroy removes the session identified by id.
+	Destroy(id string) error
+}
+
+// store is the backend selected by Open, shared by every session returned
+// from For.
+var store Store
+
+// New starts an empty session with a freshly generated ID; nothing is
+// written to the store until the session is saved.
+func New() *Session {
+	return newSession(generateToken(), store, nil)
+}
+
+// For loads the session identified by id from the configured store, or
+// starts a new one if id is empty or unknown.
+func For(id string) (*Session, error) {
+	if id == "" {
+		return New(), nil
+	}
+	values, err := store.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		return newSession(id, store, nil), nil
+	}
+	return newSession(id, store, values), nil
+}
+
+// Destroy removes the session identified by id from the configured store.
+func Destroy(id string) error {
+	if id == "" {
+		return nil
+	}
+	return store.Destroy(id)
+}
@@ -0,0 +1,89 @@
+package pulsar
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"net/http"
+
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/queue"
+)
+
+// server is the running HTTP server, set by Serve once it starts listening.
+// Shutdown uses it to trigger a graceful stop.
+var server *http.Server
+
+// shutdownErr carries the error returned by server.Shutdown so Serve can
+// surface it once ListenAndServe(TLS) unblocks with http.ErrServerClosed.
+var shutdownErr error
+
+// shutdownComplete is closed once Shutdown has finished draining the queue
+// pool, so Serve can wait for it before running its own deferred cleanup
+// instead of racing Shutdown's goroutine. Serve recreates it (and
+// shutdownOnce) on every call so a second Serve/Shutdown cycle in the same
+// process - the "useful in tests" case - doesn't close an already-closed
+// channel.
+var shutdownComplete chan struct{}
+
+// shutdownOnce guards shutdownComplete so a concurrent signal and manual
+// Shutdown() call can't race to close it twice.
+var shutdownOnce sync.Once
+
+// shutdownTimeout returns the configured grace period for draining
+// in-flight requests, defaulting to 10 seconds when unset.
+func shutdownTimeout() time.Duration {
+	if config.Settings.Server.ShutdownTimeout > 0 {
+		return config.Settings.Server.ShutdownTimeout
+	}
+	return 10 * time.Second
+}
+
+// waitForSignal blocks until SIGINT or SIGTERM is received and triggers a
+// graceful shutdown of the running server.
+func waitForSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+	if config.Settings.Server.Development {
+		log.Println("[PULSAR] Shutting down gracefully...")
+	}
+	if err := Shutdown(); err != nil {
+		log.Println("[PULSAR]", err)
+	}
+}
+
+// Shutdown gracefully stops the running server: it stops accepting new
+// connections, waits up to config.Settings.Server.ShutdownTimeout for
+// in-flight requests to finish, then drains the queue pool so no job is
+// killed mid-run. It is exported so it can be triggered programmatically,
+// e.g. from tests, in addition to the SIGINT/SIGTERM handler Serve installs.
+func Shutdown() error {
+	if server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout())
+	defer cancel()
+	shutdownErr = server.Shutdown(ctx)
+	if challengeServer != nil {
+		challengeServer.Shutdown(ctx)
+	}
+	queue.Pool.Wait()
+	shutdownOnce.Do(func() { close(shutdownComplete) })
+	return shutdownErr
+}
+
+// resetShutdownState prepares fresh shutdown bookkeeping for a new Serve
+// call, so repeated Serve/Shutdown cycles in the same process (e.g. across
+// tests) don't reuse an already-closed channel or a stale challenge server.
+func resetShutdownState() {
+	shutdownComplete = make(chan struct{})
+	shutdownOnce = sync.Once{}
+	shutdownErr = nil
+	challengeServer = nil
+}